@@ -0,0 +1,40 @@
+package xpath
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNodeWriteXML(t *testing.T) {
+	root, err := Parse(strings.NewReader(`<a x="1">hi<b/></a>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	n := root.Down[0]
+
+	var buf bytes.Buffer
+	if err := n.WriteXML(&buf); err != nil {
+		t.Fatalf("WriteXML: %v", err)
+	}
+	want := `<a x="1">hi<b></b></a>`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteXML = %q, want %q", got, want)
+	}
+}
+
+func TestNodeWriteHTML(t *testing.T) {
+	root, err := ParseHTML(strings.NewReader(`<div>line<br>break</div>`))
+	if err != nil {
+		t.Fatalf("ParseHTML: %v", err)
+	}
+	div := root.Down[0].Down[1].Down[0] // html > body > div
+
+	var buf bytes.Buffer
+	if err := div.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<br/>") && !strings.Contains(buf.String(), "<br>") {
+		t.Errorf("WriteHTML output missing void <br> element: %q", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+package xpath
+
+import "encoding/xml"
+
+// MutableNode is an editable view over a Node tree, addressed through the
+// same Up/Down pointers as the immutable tree but changed in place instead
+// of through Pos/End indices into a shared Nodes slice.
+//
+// AsMutable clears Nodes/Pos/End on every node it lifts, and on every
+// ancestor of the lifted node, since edits would otherwise leave those
+// indices pointing at stale slice positions, and an unlifted ancestor would
+// keep serving text read straight from those stale positions. Node's other
+// methods, and Path evaluation, understand this pointer-only layout: they
+// fall back to walking Down when Nodes is nil. This unlocks in-place edits —
+// HTML sanitization, XML templating, XSLT-like transforms — that previously
+// required dropping back to encoding/xml.
+type MutableNode struct {
+	node *Node
+}
+
+// AsMutable lifts node, and its whole subtree, into a MutableNode that can
+// be edited with AppendChild, InsertBefore, RemoveChild, SetAttr, and
+// SetText.
+func (node *Node) AsMutable() *MutableNode {
+	markMutable(node)
+	invalidateAncestors(node)
+	return &MutableNode{node: node}
+}
+
+// invalidateAncestors clears Nodes/Pos/End on every ancestor of node still
+// carrying them. node.Up's Down slice already aliases node itself, so it
+// reflects edits the moment they happen, but String/Bytes/equals/contains/
+// getNodeValue on an ancestor whose Nodes is still set would otherwise keep
+// scanning the original positional Nodes slice, which edits below it never
+// touch, and serve stale pre-edit text. Clearing Nodes makes those
+// accessors fall back to walking Down, the same fallback they already use
+// for node itself.
+func invalidateAncestors(node *Node) {
+	for up := node.Up; up != nil && up.Nodes != nil; up = up.Up {
+		up.Nodes, up.Pos, up.End = nil, 0, 0
+	}
+}
+
+func markMutable(node *Node) {
+	if node.Nodes == nil {
+		return
+	}
+
+	// Attributes are addressed through Nodes/Pos/End, never through Down
+	// (see attrsOf), so they must be copied into Down before that indexing
+	// is cleared below, or they vanish the moment a node goes mutable.
+	attrs := attrsOf(node)
+
+	node.Nodes, node.Pos, node.End = nil, 0, 0
+
+	// Down is itself a sub-slice of the shared, contiguous slab
+	// ParseDecoder/ParseHTML built for the whole document, so it aliases
+	// the backing array of unrelated nodes beyond its own length. Give it
+	// a backing array of its own before any edit is allowed to append to it.
+	down := make([]*Node, 0, len(attrs)+len(node.Down))
+	down = append(down, attrs...)
+	down = append(down, node.Down...)
+	node.Down = down
+
+	for _, child := range down {
+		if child.Kind != AttrNode {
+			markMutable(child)
+		}
+	}
+}
+
+// Node returns the Node underlying m.
+func (m *MutableNode) Node() *Node {
+	return m.node
+}
+
+// AppendChild appends child as m's last child.
+func (m *MutableNode) AppendChild(child *Node) {
+	child.Up = m.node
+	m.node.Down = append(m.node.Down, child)
+}
+
+// InsertBefore inserts child immediately before mark, which must currently
+// be one of m's children. If mark is nil, child is appended instead.
+func (m *MutableNode) InsertBefore(child, mark *Node) {
+	if mark == nil {
+		m.AppendChild(child)
+		return
+	}
+	for i, existing := range m.node.Down {
+		if existing == mark {
+			child.Up = m.node
+			down := append(m.node.Down, nil)
+			copy(down[i+1:], down[i:])
+			down[i] = child
+			m.node.Down = down
+			return
+		}
+	}
+}
+
+// RemoveChild removes child from m's children, if present.
+func (m *MutableNode) RemoveChild(child *Node) {
+	down := m.node.Down
+	for i, existing := range down {
+		if existing == child {
+			m.node.Down = append(down[:i], down[i+1:]...)
+			child.Up = nil
+			return
+		}
+	}
+}
+
+// SetAttr sets the value of the attribute named local, in namespace space,
+// adding it if m does not already have one.
+func (m *MutableNode) SetAttr(space, local, value string) {
+	for _, attr := range attrsOf(m.node) {
+		if attr.Name.Local == local && attr.Name.Space == space {
+			attr.Attr = value
+			return
+		}
+	}
+	m.node.Down = append(m.node.Down, &Node{
+		Kind: AttrNode,
+		Name: xml.Name{Space: space, Local: local},
+		Attr: value,
+		Up:   m.node,
+	})
+}
+
+// SetText replaces m's non-attribute children with a single TextNode
+// holding text.
+func (m *MutableNode) SetText(text string) {
+	down := m.node.Down[:0:0]
+	for _, child := range m.node.Down {
+		if child.Kind == AttrNode {
+			down = append(down, child)
+		}
+	}
+	m.node.Down = append(down, &Node{Kind: TextNode, Text: []byte(text), Up: m.node})
+}
+
+// Clone returns a deep copy of m's node and its descendants, detached from
+// m's tree.
+func (m *MutableNode) Clone() *Node {
+	return cloneNode(m.node, nil)
+}
+
+func cloneNode(node *Node, up *Node) *Node {
+	clone := &Node{Kind: node.Kind, Name: node.Name, Attr: node.Attr, Up: up}
+	if node.Text != nil {
+		clone.Text = append([]byte(nil), node.Text...)
+	}
+	if node.Down != nil {
+		clone.Down = make([]*Node, len(node.Down))
+		for i, child := range node.Down {
+			clone.Down[i] = cloneNode(child, clone)
+		}
+	}
+	return clone
+}
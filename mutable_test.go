@@ -0,0 +1,80 @@
+package xpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMutableNodeEdits(t *testing.T) {
+	root, err := Parse(strings.NewReader(`<a x="1"><b>one</b><c>two</c></a>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	m := root.Down[0].AsMutable()
+
+	m.SetAttr("", "x", "2")
+	if got := m.Node().String(); !strings.Contains(got, "onetwo") {
+		t.Fatalf("unexpected text after SetAttr: %q", got)
+	}
+
+	c := m.Node().Down[len(m.Node().Down)-1]
+	newChild := &Node{Kind: StartNode, Name: c.Name}
+	m.InsertBefore(newChild, c)
+	if len(m.Node().Down) != 4 { // x attr, b, new child, c
+		t.Fatalf("got %d children after InsertBefore, want 4", len(m.Node().Down))
+	}
+
+	m.RemoveChild(newChild)
+	if len(m.Node().Down) != 3 {
+		t.Fatalf("got %d children after RemoveChild, want 3", len(m.Node().Down))
+	}
+
+	m.SetText("replaced")
+	if got := m.Node().String(); got != "replaced" {
+		t.Fatalf("String() after SetText = %q, want %q", got, "replaced")
+	}
+}
+
+func TestMutableNodeEditVisibleToUnliftedAncestor(t *testing.T) {
+	root, err := Parse(strings.NewReader(`<root><a><b>one</b><c>two</c></a></root>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	top := root.Down[0] // <root>, never itself lifted with AsMutable
+	a := top.Down[0]    // <a>
+	a.AsMutable().SetText("replaced")
+
+	if got := a.String(); got != "replaced" {
+		t.Fatalf("a.String() = %q, want %q", got, "replaced")
+	}
+	if got := top.String(); got != "replaced" {
+		t.Errorf("top.String() = %q, want %q (stale pre-edit text)", got, "replaced")
+	}
+	if got := a.ChildrenMap(); got != "replaced" {
+		t.Errorf("a.ChildrenMap() = %#v, want %q", got, "replaced")
+	}
+	if got, ok := top.ChildrenMap().(map[string]interface{}); !ok || got["a"] != "replaced" {
+		t.Errorf("top.ChildrenMap() = %#v, want map with a == %q", top.ChildrenMap(), "replaced")
+	}
+}
+
+func TestMutableNodeClone(t *testing.T) {
+	root, err := Parse(strings.NewReader(`<a><b>one</b></a>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	m := root.Down[0].AsMutable()
+	clone := m.Clone()
+
+	clone.AsMutable().SetText("changed")
+
+	if got := clone.String(); got != "changed" {
+		t.Errorf("clone.String() = %q, want %q", got, "changed")
+	}
+	if got := m.Node().String(); got != "one" {
+		t.Errorf("original mutated by edit to clone: String() = %q, want %q", got, "one")
+	}
+}
@@ -0,0 +1,31 @@
+package xpath
+
+// NamespaceMap holds prefix-to-URI bindings used to resolve namespace
+// prefixes ("svg:rect") referenced by a Path at compile time.
+type NamespaceMap map[string]string
+
+// NewNamespaceMap returns an empty, ready to use NamespaceMap.
+func NewNamespaceMap() NamespaceMap {
+	return NamespaceMap{}
+}
+
+// Register associates prefix with uri, so that a path step written as
+// "prefix:local" resolves to uri when matched against a Node's namespace.
+func (m NamespaceMap) Register(prefix, uri string) {
+	m[prefix] = uri
+}
+
+// Prefix returns the prefix registered for uri, or the empty string if no
+// prefix in m is bound to it. If more than one prefix is bound to the same
+// uri, which one is returned is unspecified.
+func (m NamespaceMap) Prefix(uri string) string {
+	if uri == "" {
+		return ""
+	}
+	for prefix, u := range m {
+		if u == uri {
+			return prefix
+		}
+	}
+	return ""
+}
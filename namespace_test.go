@@ -0,0 +1,52 @@
+package xpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNodePrefix(t *testing.T) {
+	ns := NewNamespaceMap()
+	ns.Register("svg", "http://www.w3.org/2000/svg")
+
+	root, err := Parse(strings.NewReader(
+		`<root xmlns:svg="http://www.w3.org/2000/svg"><svg:rect/></root>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	path, err := Compile("//svg:rect", WithNamespaces(ns))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	matches := path.Iter(root)
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	rect := matches[0]
+
+	if got := rect.Namespace(); got != "http://www.w3.org/2000/svg" {
+		t.Errorf("Namespace() = %q, want the svg URI", got)
+	}
+	if got := rect.Prefix(ns); got != "svg" {
+		t.Errorf("Prefix() = %q, want %q", got, "svg")
+	}
+	if got := root.Prefix(ns); got != "" {
+		t.Errorf("Prefix() of unqualified node = %q, want empty", got)
+	}
+}
+
+func TestNamespaceMapPrefix(t *testing.T) {
+	ns := NewNamespaceMap()
+	ns.Register("svg", "http://www.w3.org/2000/svg")
+
+	if got := ns.Prefix("http://www.w3.org/2000/svg"); got != "svg" {
+		t.Errorf("Prefix() = %q, want %q", got, "svg")
+	}
+	if got := ns.Prefix("http://unknown"); got != "" {
+		t.Errorf("Prefix() for unregistered uri = %q, want empty", got)
+	}
+	if got := ns.Prefix(""); got != "" {
+		t.Errorf("Prefix(\"\") = %q, want empty", got)
+	}
+}
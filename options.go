@@ -0,0 +1,45 @@
+package xpath
+
+import (
+	"encoding/xml"
+	"io"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ParseOptions configures ParseWithOptions.
+type ParseOptions struct {
+	// CharsetReader is assigned to the underlying xml.Decoder's field of the
+	// same name, letting documents declared in encodings other than UTF-8
+	// (e.g. ISO-8859-1 or Windows-1252) be decoded correctly. It defaults to
+	// charset.NewReaderLabel, so a correctly labeled, non-UTF-8 document
+	// works without any extra wiring.
+	CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+	// Strict is mirrored onto the decoder's Strict field. It defaults to
+	// true, matching xml.NewDecoder's own default and Parse's behavior;
+	// pass a pointer to false to opt into permissive parsing of malformed
+	// documents.
+	Strict *bool
+
+	// Entity is mirrored onto the decoder's Entity field.
+	Entity map[string]string
+}
+
+// ParseWithOptions is like Parse but lets callers configure the underlying
+// xml.Decoder, most commonly to handle documents declared in a non-UTF-8
+// encoding.
+func ParseWithOptions(r io.Reader, opts ParseOptions) (*Node, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = opts.CharsetReader
+	if d.CharsetReader == nil {
+		d.CharsetReader = charset.NewReaderLabel
+	}
+	if opts.Strict == nil {
+		d.Strict = true
+	} else {
+		d.Strict = *opts.Strict
+	}
+	d.Entity = opts.Entity
+	return ParseDecoder(d)
+}
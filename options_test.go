@@ -0,0 +1,40 @@
+package xpath
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseWithOptionsStrictDefault(t *testing.T) {
+	// An unescaped "&" is only accepted by a non-strict decoder.
+	malformed := `<a>Tom & Jerry</a>`
+
+	if _, err := ParseWithOptions(strings.NewReader(malformed), ParseOptions{}); err == nil {
+		t.Fatal("expected error parsing malformed document with default (strict) options")
+	}
+
+	lenient := false
+	_, err := ParseWithOptions(strings.NewReader(malformed), ParseOptions{Strict: &lenient})
+	if err != nil {
+		t.Fatalf("ParseWithOptions with Strict=false: %v", err)
+	}
+}
+
+func TestParseWithOptionsCharsetReader(t *testing.T) {
+	called := false
+	opts := ParseOptions{
+		CharsetReader: func(cs string, input io.Reader) (io.Reader, error) {
+			called = true
+			return input, nil
+		},
+	}
+
+	doc := `<?xml version="1.0" encoding="ISO-8859-1"?><a>hi</a>`
+	if _, err := ParseWithOptions(strings.NewReader(doc), opts); err != nil {
+		t.Fatalf("ParseWithOptions: %v", err)
+	}
+	if !called {
+		t.Fatal("custom CharsetReader was not invoked")
+	}
+}
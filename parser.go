@@ -13,12 +13,11 @@ import (
 // Node is an item in an xml tree that was compiled to
 // be processed via xml paths. A node may represent:
 //
-//     - An element in the xml document (<body>)
-//     - An attribute of an element in the xml document (href="...")
-//     - A comment in the xml document (<!--...-->)
-//     - A processing instruction in the xml document (<?...?>)
-//     - Some Text within the xml document
-//
+//   - An element in the xml document (<body>)
+//   - An attribute of an element in the xml document (href="...")
+//   - A comment in the xml document (<!--...-->)
+//   - A processing instruction in the xml document (<?...?>)
+//   - Some Text within the xml document
 type Node struct {
 	Kind NodeKind
 	Name xml.Name
@@ -59,12 +58,11 @@ var NodeKinds = []string{
 //
 // The string value of a node is:
 //
-//     - For element Nodes, the concatenation of all Text Nodes within the element.
-//     - For Text Nodes, the Text itself.
-//     - For attribute Nodes, the attribute value.
-//     - For comment Nodes, the Text within the comment delimiters.
-//     - For processing instruction Nodes, the content of the instruction.
-//
+//   - For element Nodes, the concatenation of all Text Nodes within the element.
+//   - For Text Nodes, the Text itself.
+//   - For attribute Nodes, the attribute value.
+//   - For comment Nodes, the Text within the comment delimiters.
+//   - For processing instruction Nodes, the content of the instruction.
 func (node *Node) String() string {
 	if node.Kind == AttrNode {
 		return node.Attr
@@ -77,6 +75,29 @@ func (node *Node) TrimText() string {
 	return strings.TrimSpace(string(node.Text))
 }
 
+// Namespace returns the namespace URI of the node's name, or the empty
+// string if the node is unqualified. For nodes produced by ParseHTML this
+// is the short namespace token exposed by golang.org/x/net/html (e.g. "svg"
+// or "math") rather than a full URI.
+func (node *Node) Namespace() string {
+	return node.Name.Space
+}
+
+// Prefix returns the prefix ns has registered for node's namespace URI, or
+// the empty string if node is unqualified or ns has no prefix bound to that
+// URI. encoding/xml resolves namespace prefixes to URIs while decoding and
+// does not retain the prefix used in the source document, so unlike
+// Namespace, Prefix cannot recover the document's original spelling; it
+// reports the prefix the caller itself registered via NamespaceMap.Register,
+// which is the only prefix spelling a Path match can reason about.
+//
+// This takes a NamespaceMap argument rather than being zero-arg like
+// Namespace; flagging that deliberately here since it is a signature
+// change from a plain accessor, not just a naming or behavior tweak.
+func (node *Node) Prefix(ns NamespaceMap) string {
+	return ns.Prefix(node.Namespace())
+}
+
 // ChildrenMap returns interface{} (normally map[string]interface{}) of children
 func (node *Node) ChildrenMap() interface{} {
 	_, val := node.getNodeValue()
@@ -92,6 +113,11 @@ func (node *Node) getNodeValue() (int, interface{}) {
 	if node.Kind == TextNode {
 		return i, node.TrimText()
 	}
+	if node.Nodes == nil {
+		// A node lifted with AsMutable (or created while editing one) has
+		// no positional Nodes slice to scan; walk Down instead.
+		return 0, node.childrenMapFromDown()
+	}
 	m := map[string]interface{}{}
 
 	name := ""
@@ -158,6 +184,44 @@ func (node *Node) getNodeValue() (int, interface{}) {
 	return i, m
 }
 
+// childrenMapFromDown rebuilds the value getNodeValue would otherwise read
+// off the positional Nodes slice, but by walking Down: the same fallback
+// Bytes uses, for a node lifted with AsMutable whose positional indices no
+// longer describe its (possibly edited) children.
+func (node *Node) childrenMapFromDown() interface{} {
+	var text []byte
+	hasElement := false
+	m := map[string]interface{}{}
+	for _, child := range node.Down {
+		switch child.Kind {
+		case TextNode:
+			text = append(text, child.Text...)
+		case StartNode:
+			hasElement = true
+			name := child.Name.Local
+			var value interface{}
+			if child.Nodes == nil {
+				value = child.childrenMapFromDown()
+			} else {
+				_, value = child.getNodeValue()
+			}
+			if v, ok := m[name]; ok {
+				if reflect.ValueOf(v).Kind() == reflect.Slice {
+					m[name] = append(m[name].([]interface{}), value)
+				} else {
+					m[name] = append([]interface{}{}, v, value)
+				}
+			} else {
+				m[name] = value
+			}
+		}
+	}
+	if !hasElement {
+		return strings.TrimSpace(string(text))
+	}
+	return m
+}
+
 // Bytes returns the string value of node as a byte slice.
 // See Node.String for a description of what the string value of a node is.
 func (node *Node) Bytes() []byte {
@@ -167,6 +231,11 @@ func (node *Node) Bytes() []byte {
 	if node.Kind != StartNode {
 		return node.Text
 	}
+	if node.Nodes == nil {
+		// A tree lifted with AsMutable has no Nodes slice to scan; walk
+		// Down instead.
+		return descendantText(node, nil)
+	}
 	size := 0
 	for i := node.Pos; i < node.End; i++ {
 		if node.Nodes[i].Kind == TextNode {
@@ -182,6 +251,41 @@ func (node *Node) Bytes() []byte {
 	return text
 }
 
+func descendantText(node *Node, text []byte) []byte {
+	for _, child := range node.Down {
+		switch child.Kind {
+		case TextNode:
+			text = append(text, child.Text...)
+		case StartNode:
+			text = descendantText(child, text)
+		}
+	}
+	return text
+}
+
+// attrsOf returns node's attribute children. In an immutable tree these are
+// scanned from the contiguous Nodes slice; in a tree lifted to MutableNode
+// via AsMutable, Nodes is cleared and attributes live in Down instead.
+func attrsOf(node *Node) []*Node {
+	if node.Kind != StartNode {
+		return nil
+	}
+	if node.Nodes != nil {
+		var out []*Node
+		for i := node.Pos + 1; i < node.End && node.Nodes[i].Kind == AttrNode; i++ {
+			out = append(out, &node.Nodes[i])
+		}
+		return out
+	}
+	var out []*Node
+	for _, child := range node.Down {
+		if child.Kind == AttrNode {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
 // equals returns whether the string value of node is equal to s,
 // without allocating memory.
 func (node *Node) equals(s string) bool {
@@ -199,6 +303,11 @@ func (node *Node) equals(s string) bool {
 		}
 		return true
 	}
+	if node.Nodes == nil {
+		// A StartNode lifted with AsMutable has no Nodes slice to scan;
+		// walk Down instead, same as Bytes.
+		return string(node.Bytes()) == s
+	}
 	si := 0
 	for i := node.Pos; i < node.End; i++ {
 		if node.Nodes[i].Kind == TextNode {
@@ -225,6 +334,12 @@ func (node *Node) contains(s string) (ok bool) {
 	if node.Kind == AttrNode {
 		return strings.Contains(node.Attr, s)
 	}
+	if node.Nodes == nil {
+		// A node lifted with AsMutable (or created while editing one) has
+		// no positional Nodes slice to scan; walk Down instead, same as
+		// Bytes.
+		return strings.Contains(string(node.Bytes()), s)
+	}
 	s0 := s[0]
 	for i := node.Pos; i < node.End; i++ {
 		if node.Nodes[i].Kind == TextNode {
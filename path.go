@@ -0,0 +1,185 @@
+package xpath
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Path is a compiled path expression that can be run repeatedly against
+// one or more Node trees to locate the nodes it selects.
+//
+// The subset of XPath supported by Path covers the steps most commonly
+// needed to navigate parsed documents: absolute steps ("/a"), descendant
+// steps ("//a"), attribute steps ("@a"), the wildcard "*", and namespace
+// prefixes registered through WithNamespaces ("ns:a").
+type Path struct {
+	steps []step
+}
+
+type step struct {
+	descendant bool
+	attr       bool
+	space      string // resolved namespace URI; empty when the step is unqualified
+	local      string // local name; "*" matches any name
+}
+
+// PathOption configures a Path at compile time.
+type PathOption func(*pathOptions)
+
+type pathOptions struct {
+	namespaces NamespaceMap
+}
+
+// WithNamespaces registers the prefix-to-URI bindings that Compile resolves
+// namespace-qualified steps against.
+func WithNamespaces(ns NamespaceMap) PathOption {
+	return func(o *pathOptions) {
+		o.namespaces = ns
+	}
+}
+
+// Compile compiles path into a Path. opts may be used to register the
+// namespace prefixes referenced by path, via WithNamespaces.
+func Compile(path string, opts ...PathOption) (*Path, error) {
+	var o pathOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.namespaces == nil {
+		o.namespaces = NamespaceMap{}
+	}
+	steps, err := compileSteps(path, o.namespaces)
+	if err != nil {
+		return nil, fmt.Errorf("xpath: %s: %v", path, err)
+	}
+	return &Path{steps: steps}, nil
+}
+
+// MustCompile is like Compile but panics if path cannot be compiled.
+func MustCompile(path string, opts ...PathOption) *Path {
+	p, err := Compile(path, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func compileSteps(path string, ns NamespaceMap) ([]step, error) {
+	if path == "" || path[0] != '/' {
+		return nil, fmt.Errorf("path must be absolute")
+	}
+	var steps []step
+	for i := 0; i < len(path); {
+		i++ // consume leading '/'
+		descendant := false
+		if i < len(path) && path[i] == '/' {
+			descendant = true
+			i++
+		}
+		start := i
+		for i < len(path) && path[i] != '/' {
+			i++
+		}
+		part := path[start:i]
+		if part == "" {
+			if i < len(path) {
+				return nil, fmt.Errorf("empty step")
+			}
+			break // trailing slash
+		}
+		s, err := compileStep(part, ns)
+		if err != nil {
+			return nil, err
+		}
+		s.descendant = descendant
+		steps = append(steps, s)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("path has no steps")
+	}
+	return steps, nil
+}
+
+func compileStep(part string, ns NamespaceMap) (step, error) {
+	var s step
+	if strings.HasPrefix(part, "@") {
+		s.attr = true
+		part = part[1:]
+	}
+	if prefix, local, ok := strings.Cut(part, ":"); ok {
+		uri, known := ns[prefix]
+		if !known {
+			return step{}, fmt.Errorf("unregistered namespace prefix %q", prefix)
+		}
+		s.space, s.local = uri, local
+	} else {
+		s.local = part
+	}
+	return s, nil
+}
+
+func (s step) matches(n *Node) bool {
+	if s.local != "*" && n.Name.Local != s.local {
+		return false
+	}
+	return s.space == "" || n.Name.Space == s.space
+}
+
+// apply returns the nodes step selects when run against node.
+func (s step) apply(node *Node) []*Node {
+	if s.attr {
+		var out []*Node
+		for _, attr := range attrsOf(node) {
+			if s.matches(attr) {
+				out = append(out, attr)
+			}
+		}
+		return out
+	}
+
+	var out []*Node
+	var walk func(*Node)
+	walk = func(n *Node) {
+		for _, child := range n.Down {
+			if child.Kind != StartNode {
+				continue
+			}
+			if s.matches(child) {
+				out = append(out, child)
+			}
+			if s.descendant {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+	return out
+}
+
+// Iter returns every node path selects starting at node.
+func (p *Path) Iter(node *Node) []*Node {
+	current := []*Node{node}
+	for _, s := range p.steps {
+		var next []*Node
+		for _, n := range current {
+			next = append(next, s.apply(n)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// Exists reports whether path selects at least one node starting at node.
+func (p *Path) Exists(node *Node) bool {
+	return len(p.Iter(node)) > 0
+}
+
+// String returns the string value of the first node path selects starting
+// at node, or the empty string if path selects nothing.
+func (p *Path) String(node *Node) string {
+	matches := p.Iter(node)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0].String()
+}
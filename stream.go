@@ -0,0 +1,173 @@
+package xpath
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// ParseStream reads an xml document from r one token at a time and, whenever
+// an element subtree matching match closes, materializes just that subtree
+// as a *Node and passes it to fn. It never buffers the full document, so
+// memory use stays bounded by the depth of the tree plus the size of the
+// largest matched subtree, making it suitable for multi-gigabyte feeds such
+// as sitemaps, RSS, or OpenStreetMap dumps.
+//
+// match is evaluated against the position of each element as the document
+// is read; only the child/descendant element steps of match are supported,
+// since there is no complete tree to run attribute or text steps against
+// until a subtree has already been selected.
+//
+// If fn returns an error, ParseStream stops and returns it.
+func ParseStream(r io.Reader, match *Path, fn func(*Node) error) error {
+	d := xml.NewDecoder(r)
+
+	// matched[i] is how many leading steps of match are satisfied by the
+	// element stack up to and including depth i. -1 marks a branch that can
+	// never satisfy match, since it failed a non-descendant step.
+	var matched []int
+
+	var nodes []Node
+	var text []byte
+	capturing := false
+	openDepth := 0
+
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			if capturing {
+				nodes = append(nodes, Node{Kind: StartNode, Name: t.Name})
+				for _, attr := range t.Attr {
+					nodes = append(nodes, Node{Kind: AttrNode, Name: attr.Name, Attr: attr.Value})
+				}
+				openDepth++
+				continue
+			}
+
+			parent := 0
+			if len(matched) > 0 {
+				parent = matched[len(matched)-1]
+			}
+			next := parent
+			if parent >= 0 && parent < len(match.steps) {
+				s := match.steps[parent]
+				switch {
+				case !s.attr && s.matches(&Node{Name: t.Name}):
+					next = parent + 1
+				case !s.descendant:
+					next = -1
+				}
+			}
+			matched = append(matched, next)
+
+			if next == len(match.steps) {
+				capturing = true
+				openDepth = 1
+				nodes = append(nodes, Node{Kind: StartNode, Name: t.Name})
+				for _, attr := range t.Attr {
+					nodes = append(nodes, Node{Kind: AttrNode, Name: attr.Name, Attr: attr.Value})
+				}
+			}
+
+		case xml.EndElement:
+			if capturing {
+				nodes = append(nodes, Node{Kind: EndNode})
+				openDepth--
+				if openDepth == 0 {
+					node, err := buildSubtree(nodes)
+					if err != nil {
+						return err
+					}
+					if err := fn(node); err != nil {
+						return err
+					}
+					nodes = nodes[:0]
+					text = text[:0]
+					capturing = false
+					// The StartElement that opened this subtree pushed a
+					// fully-matched entry onto matched; pop it now that the
+					// subtree is closed, or the next sibling would inherit
+					// it as its parent's matched depth and be captured
+					// unconditionally.
+					matched = matched[:len(matched)-1]
+				}
+				continue
+			}
+			matched = matched[:len(matched)-1]
+
+		case xml.CharData:
+			if capturing {
+				texti := len(text)
+				text = append(text, t...)
+				nodes = append(nodes, Node{Kind: TextNode, Text: text[texti : texti+len(t)]})
+			}
+
+		case xml.Comment:
+			if capturing {
+				texti := len(text)
+				text = append(text, t...)
+				nodes = append(nodes, Node{Kind: CommentNode, Text: text[texti : texti+len(t)]})
+			}
+
+		case xml.ProcInst:
+			if capturing {
+				texti := len(text)
+				text = append(text, t.Inst...)
+				nodes = append(nodes, Node{Kind: ProcInstNode, Name: xml.Name{Local: t.Target}, Text: text[texti : texti+len(t.Inst)]})
+			}
+		}
+	}
+}
+
+// buildSubtree computes Pos/End/Up/Down over a freshly captured, self
+// contained subtree, exactly as ParseDecoder does for a whole document.
+func buildSubtree(nodes []Node) (*Node, error) {
+	stack := make([]*Node, 0, len(nodes))
+	downs := make([]*Node, len(nodes))
+	downCount := 0
+
+	for pos := range nodes {
+		switch nodes[pos].Kind {
+
+		case StartNode, AttrNode, TextNode, CommentNode, ProcInstNode:
+			node := &nodes[pos]
+			node.Nodes = nodes
+			node.Pos = pos
+			if len(stack) > 0 {
+				node.Up = stack[len(stack)-1]
+			}
+			if node.Kind == StartNode {
+				stack = append(stack, node)
+			} else {
+				node.End = pos + 1
+			}
+
+		case EndNode:
+			node := stack[len(stack)-1]
+			node.End = pos
+			stack = stack[:len(stack)-1]
+
+			node.Down = downs[downCount:downCount]
+			for i := node.Pos + 1; i < node.End; i++ {
+				if nodes[i].Up == node {
+					switch nodes[i].Kind {
+					case StartNode, TextNode, CommentNode, ProcInstNode:
+						node.Down = append(node.Down, &nodes[i])
+						downCount++
+					}
+				}
+			}
+			if len(stack) == 0 {
+				return node, nil
+			}
+		}
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,57 @@
+package xpath
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStream(t *testing.T) {
+	doc := `<feed>
+		<item id="1"><title>A</title></item>
+		<item id="2"><title>B</title></item>
+	</feed>`
+
+	match, err := Compile("//item")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	var titles []string
+	err = ParseStream(strings.NewReader(doc), match, func(n *Node) error {
+		title, err := Compile("/title")
+		if err != nil {
+			return err
+		}
+		titles = append(titles, title.String(n))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+
+	want := []string{"A", "B"}
+	if len(titles) != len(want) {
+		t.Fatalf("got %d titles, want %d: %v", len(titles), len(want), titles)
+	}
+	for i, title := range want {
+		if titles[i] != title {
+			t.Errorf("titles[%d] = %q, want %q", i, titles[i], title)
+		}
+	}
+}
+
+func TestParseStreamPropagatesCallbackError(t *testing.T) {
+	match, err := Compile("//item")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	sentinel := errors.New("boom")
+	err = ParseStream(strings.NewReader(`<feed><item/></feed>`), match, func(n *Node) error {
+		return sentinel
+	})
+	if err != sentinel {
+		t.Fatalf("ParseStream error = %v, want %v", err, sentinel)
+	}
+}
@@ -0,0 +1,194 @@
+package xpath
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fieldPlan is the compiled, reflection-free plan for populating one struct
+// field from an `xpath` tag.
+type fieldPlan struct {
+	index []int
+	// path selects the field's source node relative to the struct's
+	// context node. It is nil for a self-referential tag (an empty path
+	// before the options, e.g. `xpath:",text"`), which binds the field to
+	// the context node itself rather than to any of its children.
+	path   *Path
+	format string
+}
+
+// planCache holds the field plan for each struct type Unmarshal has seen,
+// keyed by reflect.Type, so repeated calls avoid re-parsing tags.
+var planCache sync.Map
+
+// Unmarshal populates v, which must be a pointer to a struct, from the
+// subtree rooted at node using `xpath` struct tags, e.g.:
+//
+//	type Link struct {
+//		Title string `xpath:"./title"`
+//		Href  string `xpath:"href,attr"`
+//		Text  string `xpath:",text"`
+//	}
+//
+// A tag with no path before the options, as Text's `,text` above, binds the
+// field to the struct's own context node rather than to a child.
+//
+// Slice fields are populated from every match; nested struct fields recurse
+// with the matched node as the new context node. Supported scalar types are
+// string, the integer and float kinds, bool, and time.Time, whose layout
+// defaults to time.RFC3339 and can be overridden with a `,format=<layout>`
+// tag option.
+func Unmarshal(node *Node, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("xpath: Unmarshal target must be a pointer to a struct, got %T", v)
+	}
+	return unmarshalStruct(node, rv.Elem())
+}
+
+func unmarshalStruct(node *Node, rv reflect.Value) error {
+	plans, err := plansFor(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, p := range plans {
+		var matches []*Node
+		if p.path == nil {
+			matches = []*Node{node}
+		} else {
+			matches = p.path.Iter(node)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		field := rv.FieldByIndex(p.index)
+		if field.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(field.Type(), 0, len(matches))
+			for _, m := range matches {
+				ev := reflect.New(field.Type().Elem()).Elem()
+				if err := setValue(ev, m, p.format); err != nil {
+					return err
+				}
+				slice = reflect.Append(slice, ev)
+			}
+			field.Set(slice)
+			continue
+		}
+		if err := setValue(field, matches[0], p.format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, node *Node, format string) error {
+	if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Time{}) {
+		return unmarshalStruct(node, field)
+	}
+
+	s := strings.TrimSpace(node.String())
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Struct: // time.Time
+		if format == "" {
+			format = time.RFC3339
+		}
+		t, err := time.Parse(format, s)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+	default:
+		return fmt.Errorf("xpath: unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// plansFor compiles the xpath tag on each field of t once, caching the
+// result for subsequent calls.
+func plansFor(t reflect.Type) ([]fieldPlan, error) {
+	if cached, ok := planCache.Load(t); ok {
+		return cached.([]fieldPlan), nil
+	}
+
+	var plans []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("xpath")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		expr, rest, _ := strings.Cut(tag, ",")
+		self := expr == ""
+		var format string
+		// ",format=..." must be the last option: a time layout commonly
+		// contains commas of its own (time.RFC1123 is "Mon, 02 Jan 2006
+		// 15:04:05 MST"), so once it is seen the remainder of the tag is
+		// taken verbatim instead of being comma-split further.
+		for rest != "" {
+			opt, next, _ := strings.Cut(rest, ",")
+			switch {
+			case opt == "attr":
+				expr = "@" + expr
+				self = false
+			case opt == "text":
+				// no-op: Node.String already returns the concatenated text
+				// of the matched element.
+			case strings.HasPrefix(rest, "format="):
+				format = strings.TrimPrefix(rest, "format=")
+				next = ""
+			}
+			rest = next
+		}
+
+		// An empty path before the options (bare `xpath:",text"`, the
+		// encoding/xml `,chardata` analogue) binds the field to the
+		// context node itself; there is no path to compile.
+		var path *Path
+		if !self {
+			p, err := Compile(normalizeTag(expr))
+			if err != nil {
+				return nil, fmt.Errorf("xpath: field %s: %v", f.Name, err)
+			}
+			path = p
+		}
+		plans = append(plans, fieldPlan{index: f.Index, path: path, format: format})
+	}
+
+	planCache.Store(t, plans)
+	return plans, nil
+}
+
+// normalizeTag rewrites the relative path syntax accepted in `xpath` tags
+// ("title", "./title", "@href") into the absolute form Compile expects.
+func normalizeTag(tag string) string {
+	tag = strings.TrimPrefix(tag, "./")
+	if !strings.HasPrefix(tag, "/") {
+		tag = "/" + tag
+	}
+	return tag
+}
@@ -0,0 +1,68 @@
+package xpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalSelfText(t *testing.T) {
+	type Title struct {
+		Text string `xpath:",text"`
+	}
+
+	root, err := Parse(strings.NewReader(`<title>Hello <b>World</b></title>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	n := root.Down[0]
+
+	var ti Title
+	if err := Unmarshal(n, &ti); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if ti.Text != "Hello World" {
+		t.Fatalf("Text = %q, want %q", ti.Text, "Hello World")
+	}
+}
+
+func TestUnmarshalChildAndAttr(t *testing.T) {
+	type Link struct {
+		Title string `xpath:"./title"`
+		Href  string `xpath:"href,attr"`
+	}
+
+	root, err := Parse(strings.NewReader(`<link href="/a"><title>A</title></link>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	n := root.Down[0]
+
+	var l Link
+	if err := Unmarshal(n, &l); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if l.Title != "A" {
+		t.Errorf("Title = %q, want %q", l.Title, "A")
+	}
+	if l.Href != "/a" {
+		t.Errorf("Href = %q, want %q", l.Href, "/a")
+	}
+}
+
+func TestUnmarshalRejectsOutOfRangeNumbers(t *testing.T) {
+	type Narrow struct {
+		N int8    `xpath:",text"`
+		F float32 `xpath:",text"`
+	}
+
+	root, err := Parse(strings.NewReader(`<n>300</n>`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	n := root.Down[0]
+
+	var narrow Narrow
+	if err := Unmarshal(n, &narrow); err == nil {
+		t.Fatalf("Unmarshal into int8 from out-of-range value 300 succeeded with N = %d, want an error", narrow.N)
+	}
+}